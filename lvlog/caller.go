@@ -0,0 +1,58 @@
+package lvlog
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+)
+
+// Flags recognized by SetFlags, controlling what call-site metadata
+// gets attached to each log record.
+const (
+	FlagCaller = 1 << iota // attach the user's file:line
+	FlagFunc               // additionally attach the calling function's name
+)
+
+var outputFlags int32
+
+// SetFlags sets which call-site metadata outputf/output attach to
+// each record, as an OR of the Flag* constants. The default (0)
+// attaches nothing.
+func SetFlags(flags int) {
+	atomic.StoreInt32(&outputFlags, int32(flags))
+}
+
+// callerSkip is the number of stack frames runtime.Caller must ascend
+// from callerAttrs to reach the user's call site. It accounts for
+// callerAttrs itself, the Logger.outputf/output method, and the
+// package-level outputf/output and Debugf/Errorf/... wrappers.
+var callerSkip int32 = 4
+
+// SetCallerSkip adjusts callerSkip for callers that wrap
+// Debugf/Infof/... (or Trace/Error/...) in their own helper
+// functions, each of which adds one more frame to skip.
+func SetCallerSkip(n int) {
+	atomic.StoreInt32(&callerSkip, int32(n))
+}
+
+// callerAttrs returns the slog attributes requested via SetFlags for
+// the user's call site, replacing the old (and wrong-frame)
+// golog.Lshortfile behavior with an explicit, skip-adjustable
+// runtime.Caller lookup. It returns nil when FlagCaller is unset.
+func callerAttrs() []any {
+	flags := atomic.LoadInt32(&outputFlags)
+	if flags&FlagCaller == 0 {
+		return nil
+	}
+	pc, file, line, ok := runtime.Caller(int(atomic.LoadInt32(&callerSkip)))
+	if !ok {
+		return nil
+	}
+	attrs := []any{"source", fmt.Sprintf("%s:%d", file, line)}
+	if flags&FlagFunc != 0 {
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			attrs = append(attrs, "func", fn.Name())
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,58 @@
+package lvlog
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// withFlags points outputFlags/callerSkip at the given values and
+// restores the prior configuration afterwards.
+func withFlags(t *testing.T, flags int, skip int) {
+	t.Helper()
+	origFlags, origSkip := outputFlags, callerSkip
+	SetFlags(flags)
+	SetCallerSkip(skip)
+	t.Cleanup(func() {
+		SetFlags(int(origFlags))
+		SetCallerSkip(int(origSkip))
+	})
+}
+
+func TestFlagCallerReportsUsersCallSite(t *testing.T) {
+	var buf bytes.Buffer
+	withStdConfig(t, LevelInfo, map[int]io.Writer{LevelInfo: &buf})
+	withFlags(t, FlagCaller, int(callerSkip))
+
+	_, wantFile, callerLine, ok := runtime.Caller(0)
+	Infof("hello")
+	if !ok {
+		t.Fatal("runtime.Caller(0) failed")
+	}
+	wantLine := callerLine + 1
+
+	out := buf.String()
+	if strings.Contains(out, "caller.go") {
+		t.Fatalf("source attr points at caller.go instead of the call site: %q", out)
+	}
+	wantSuffix := filepath.Base(wantFile) + ":" + strconv.Itoa(wantLine)
+	if !strings.Contains(out, wantSuffix) {
+		t.Fatalf("got %q, want source attr ending in %q", out, wantSuffix)
+	}
+}
+
+func TestFlagCallerOmittedWithoutFlag(t *testing.T) {
+	var buf bytes.Buffer
+	withStdConfig(t, LevelInfo, map[int]io.Writer{LevelInfo: &buf})
+	withFlags(t, 0, int(callerSkip))
+
+	Infof("hello")
+
+	if strings.Contains(buf.String(), "source=") {
+		t.Fatalf("expected no source attr without FlagCaller, got %q", buf.String())
+	}
+}
@@ -12,51 +12,56 @@ import (
 	"errors"
 	"flag"
 	"fmt"
-	golog "log"
+	"io"
 	"os"
 )
 
 // The following constants represent logging levels in increasing levels of seriousness.
 const (
-	LevelDebug = iota
+	LevelTrace = iota
+	LevelDebug
 	LevelInfo
 	LevelWarning
 	LevelError
 )
 
-var levelPrefix = [...]string{
-	LevelDebug:   "[DEBUG] ",
-	LevelInfo:    "[INFO] ",
-	LevelWarning: "[WARNING] ",
-	LevelError:   "[ERROR] ",
-}
+// numLevels is the number of Level* constants, i.e. the width of the
+// per-level arrays (writers, loggers, samplers, stats) in this package.
+const numLevels = LevelError + 1
 
 // Level stores the current logging level.
 var level = LevelInfo
-var logFile = os.Stderr
+
+// logFile is the destination used by InitFromArgs/InitLevelAndFile,
+// which route every level to the same file. Callers that need
+// per-level destinations and rotation should use
+// InitLevelAndWriters instead.
+var logFile io.Writer = os.Stderr
 
 var levelString = "INFO"
 var filename = ""
-var logger golog.Logger
 
 func init() {
-	flag.StringVar(&levelString, "log-level", "INFO", "log level, can only be DEBUG, INFO, WARNING, ERROR")
+	flag.StringVar(&levelString, "log-level", "INFO", "log level, can only be TRACE, DEBUG, INFO, WARNING, ERROR")
 	flag.StringVar(&filename, "log-file", "", "log file name")
 }
 
 func setFileName(name string) error {
 	var err error
 	if len(name) > 0 {
-		logFile, err = os.OpenFile(name, os.O_RDWR, 0666)
+		logFile, err = openRotatingFile(name, FilePerm)
+	} else {
+		logFile = os.Stderr
 	}
-	golog.SetOutput(logFile)
-	golog.SetFlags(golog.LstdFlags | golog.Lshortfile)
+	std.init(level, [numLevels]io.Writer{logFile, logFile, logFile, logFile, logFile})
 	return err
 }
 
 // InitFromArgs is the preferred way to init this library.
 func InitFromArgs() {
 	switch levelString {
+	case "TRACE":
+		level = LevelTrace
 	case "DEBUG":
 		level = LevelDebug
 	case "INFO":
@@ -66,7 +71,13 @@ func InitFromArgs() {
 	case "ERROR":
 		level = LevelError
 	default:
-		fmt.Fprintln(os.Stderr, "log-level can only be DEBUG, INFO, WARNING, ERROR")
+		fmt.Fprintln(os.Stderr, "log-level can only be TRACE, DEBUG, INFO, WARNING, ERROR")
+		os.Exit(1)
+	}
+	switch logFormat {
+	case FormatText, FormatJSON:
+	default:
+		fmt.Fprintln(os.Stderr, "log-format can only be text or json")
 		os.Exit(1)
 	}
 	setFileName(filename)
@@ -74,7 +85,7 @@ func InitFromArgs() {
 
 // InitLevelAndFile is used to manually init this library
 func InitLevelAndFile(l int, p string) error {
-	if l <= LevelError && l >= LevelDebug {
+	if l <= LevelError && l >= LevelTrace {
 		level = l
 	} else {
 		return errors.New("wrong level number")
@@ -83,15 +94,11 @@ func InitLevelAndFile(l int, p string) error {
 }
 
 func outputf(l int, format string, v []interface{}) {
-	if l >= level {
-		golog.Printf(fmt.Sprint(levelPrefix[l], format), v...)
-	}
+	std.outputf(l, format, v)
 }
 
 func output(l int, v []interface{}) {
-	if l >= level {
-		golog.Print(levelPrefix[l], fmt.Sprint(v...))
-	}
+	std.output(l, v)
 }
 
 // Fatalf logs a formatted message at the "critical" level. The
@@ -150,3 +157,15 @@ func Debugf(format string, v ...interface{}) {
 func Debug(v ...interface{}) {
 	output(LevelDebug, v)
 }
+
+// Tracef logs a formatted message at the "trace" level, for detail
+// finer-grained than Debugf. The arguments are handled in the same
+// manner as fmt.Printf.
+func Tracef(format string, v ...interface{}) {
+	outputf(LevelTrace, format, v)
+}
+
+// Trace logs its arguments at the "trace" level.
+func Trace(v ...interface{}) {
+	output(LevelTrace, v)
+}
@@ -0,0 +1,93 @@
+package lvlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// Logger multiplexes log output across one destination per level, so
+// e.g. ERROR+ can go to stderr or a dedicated errors.log while
+// INFO/DEBUG go elsewhere (or io.Discard). The package-level
+// Debugf/Infof/... functions are backed by std, the process-wide
+// Logger configured by InitFromArgs/InitLevelAndFile/InitLevelAndWriters.
+type Logger struct {
+	level   int
+	writers [numLevels]io.Writer
+	loggers [numLevels]*slog.Logger
+}
+
+// std is the Logger backing the package-level Debugf/Infof/... family.
+var std = &Logger{
+	level:   LevelInfo,
+	writers: [numLevels]io.Writer{os.Stderr, os.Stderr, os.Stderr, os.Stderr, os.Stderr},
+}
+
+func init() {
+	std.rebuild()
+}
+
+// init reconfigures lg to log at lvl, with writers giving the
+// destination for each level.
+func (lg *Logger) init(lvl int, writers [numLevels]io.Writer) {
+	lg.level = lvl
+	lg.writers = writers
+	lg.rebuild()
+}
+
+func (lg *Logger) rebuild() {
+	for i, w := range lg.writers {
+		if w == nil {
+			w = os.Stderr
+		}
+		lg.loggers[i] = slog.New(newHandler(w, lg.level))
+	}
+}
+
+// loggerFor returns lg's logger for level, used as the fallback
+// destination for the context-aware DebugCtx/InfoCtx/.../FromContext/
+// With API when a caller hasn't attached its own logger via
+// NewContext.
+func (lg *Logger) loggerFor(level int) *slog.Logger {
+	return lg.loggers[level]
+}
+
+func (lg *Logger) outputf(l int, format string, v []interface{}) {
+	if l < lg.level || !sampleAllow(l, formatKey(format)) {
+		return
+	}
+	lg.loggers[l].Log(context.Background(), toSlogLevel(l), fmt.Sprintf(format, v...), callerAttrs()...)
+}
+
+func (lg *Logger) output(l int, v []interface{}) {
+	if l < lg.level || !sampleAllow(l, 0) {
+		return
+	}
+	lg.loggers[l].Log(context.Background(), toSlogLevel(l), fmt.Sprint(v...), callerAttrs()...)
+}
+
+// InitLevelAndWriters configures the package-level logger to log at
+// lvl and above, directing each level in writers to its own
+// destination. Levels absent from writers keep their current
+// destination (os.Stderr if never configured). This is the preferred
+// way to init the package in long-running services, where ERROR+
+// typically wants a different (or differently rotated) destination
+// than INFO/DEBUG.
+func InitLevelAndWriters(lvl int, writers map[int]io.Writer) error {
+	if lvl < LevelTrace || lvl > LevelError {
+		return errors.New("wrong level number")
+	}
+	next := std.writers
+	for l, w := range writers {
+		if l < LevelTrace || l > LevelError {
+			return errors.New("wrong level number")
+		}
+		next[l] = w
+	}
+	level = lvl
+	std.init(lvl, next)
+	return nil
+}
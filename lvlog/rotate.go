@@ -0,0 +1,110 @@
+package lvlog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// MaxSizeBytes is the size, in bytes, a rotating log file is allowed
+// to grow to before it is rotated. Zero (the default) disables
+// rotation.
+var MaxSizeBytes int64
+
+// MaxBackups is the number of rotated backups (name.1, name.2, ...)
+// kept around before the oldest is removed. It has no effect when
+// MaxSizeBytes is zero. When MaxSizeBytes is set but MaxBackups is
+// left at zero, a file that crosses MaxSizeBytes is truncated in
+// place instead of rotated, since there is nowhere to shift its
+// content to.
+var MaxBackups int
+
+// FilePerm is the permission mode used when a log file is created.
+var FilePerm os.FileMode = 0640
+
+// rotatingFile is an io.Writer backed by an *os.File that rotates
+// itself to name.1, name.2, ... before a write would take it past
+// MaxSizeBytes.
+type rotatingFile struct {
+	mu   sync.Mutex
+	name string
+	perm os.FileMode
+	file *os.File
+	size int64
+}
+
+// openRotatingFile opens (creating if necessary) name for appending,
+// ready to be rotated according to MaxSizeBytes/MaxBackups.
+func openRotatingFile(name string, perm os.FileMode) (*rotatingFile, error) {
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, perm)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &rotatingFile{name: name, perm: perm, file: f, size: info.Size()}, nil
+}
+
+func (w *rotatingFile) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if MaxSizeBytes > 0 && w.size+int64(len(p)) > MaxSizeBytes {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// rotate makes room in the current file when the next write would
+// take it past MaxSizeBytes. With MaxBackups==0 there's nowhere to shift old
+// content to, so it truncates name in place; otherwise it closes the
+// file, shifts existing backups up by one generation (dropping
+// anything past MaxBackups), and reopens name fresh. The caller must
+// hold w.mu. It stops and returns the first error encountered,
+// leaving later generations unshifted rather than losing track of a
+// failure partway through.
+func (w *rotatingFile) rotate() error {
+	if MaxBackups <= 0 {
+		if err := w.file.Truncate(0); err != nil {
+			return err
+		}
+		if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		w.size = 0
+		return nil
+	}
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	oldest := fmt.Sprintf("%s.%d", w.name, MaxBackups)
+	if err := os.Remove(oldest); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for n := MaxBackups - 1; n >= 1; n-- {
+		src := fmt.Sprintf("%s.%d", w.name, n)
+		if _, err := os.Stat(src); os.IsNotExist(err) {
+			continue
+		}
+		if err := os.Rename(src, fmt.Sprintf("%s.%d", w.name, n+1)); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(w.name, fmt.Sprintf("%s.1", w.name)); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(w.name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, w.perm)
+	if err != nil {
+		return err
+	}
+	w.file = f
+	w.size = 0
+	return nil
+}
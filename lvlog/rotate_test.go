@@ -0,0 +1,85 @@
+package lvlog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func withRotationConfig(t *testing.T, maxSize int64, maxBackups int) {
+	t.Helper()
+	origSize, origBackups := MaxSizeBytes, MaxBackups
+	MaxSizeBytes, MaxBackups = maxSize, maxBackups
+	t.Cleanup(func() { MaxSizeBytes, MaxBackups = origSize, origBackups })
+}
+
+func TestRotatingFileTruncatesInPlaceWithoutBackups(t *testing.T) {
+	withRotationConfig(t, 10, 0)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := openRotatingFile(path, 0640)
+	if err != nil {
+		t.Fatalf("openRotatingFile: %v", err)
+	}
+
+	if _, err := w.Write([]byte("0123456789")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	// Crosses MaxSizeBytes, should truncate rather than rotate.
+	if _, err := w.Write([]byte("abcde")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	if _, err := os.Stat(path + ".1"); !os.IsNotExist(err) {
+		t.Fatalf("expected no backup file to be created, stat err = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != "abcde" {
+		t.Fatalf("got file contents %q, want %q (truncated, not appended)", data, "abcde")
+	}
+	if w.size != int64(len("abcde")) {
+		t.Fatalf("got w.size = %d, want %d", w.size, len("abcde"))
+	}
+}
+
+func TestRotatingFileShiftsBackups(t *testing.T) {
+	withRotationConfig(t, 10, 2)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := openRotatingFile(path, 0640)
+	if err != nil {
+		t.Fatalf("openRotatingFile: %v", err)
+	}
+
+	writes := []string{
+		"AAAAAAAAAA", // fills to exactly MaxSizeBytes, no rotate yet
+		"B",          // crosses the threshold: rotate, then write "B"
+		"C",          // fits alongside "B", no rotate
+		"DDDDDDDDD",  // crosses again: rotate "BC" into .1, "AAAAAAAAAA" into .2
+	}
+	for _, s := range writes {
+		if _, err := w.Write([]byte(s)); err != nil {
+			t.Fatalf("Write(%q): %v", s, err)
+		}
+	}
+
+	wantCur, wantGen1, wantGen2 := "DDDDDDDDD", "BC", "AAAAAAAAAA"
+	if cur, err := os.ReadFile(path); err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	} else if string(cur) != wantCur {
+		t.Fatalf("got current file = %q, want %q", cur, wantCur)
+	}
+	if gen1, err := os.ReadFile(path + ".1"); err != nil {
+		t.Fatalf("ReadFile .1: %v", err)
+	} else if string(gen1) != wantGen1 {
+		t.Fatalf("got .1 = %q, want %q", gen1, wantGen1)
+	}
+	if gen2, err := os.ReadFile(path + ".2"); err != nil {
+		t.Fatalf("ReadFile .2: %v", err)
+	} else if string(gen2) != wantGen2 {
+		t.Fatalf("got .2 = %q, want %q", gen2, wantGen2)
+	}
+}
@@ -0,0 +1,146 @@
+package lvlog
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// Sampler decides whether a message identified by key (the call
+// site's format string, see formatKey; the Debug/Info/... variants
+// without a format string all share key 0) should be emitted right now.
+// Implementations must be safe for concurrent use.
+type Sampler interface {
+	Allow(key uintptr) bool
+}
+
+// LevelStats reports how many messages a level has emitted versus
+// dropped due to a configured Sampler.
+type LevelStats struct {
+	Emitted uint64
+	Dropped uint64
+}
+
+var (
+	samplerMu sync.RWMutex
+	samplers  [numLevels]Sampler
+	stats     [numLevels]LevelStats
+)
+
+// SetSampler installs s as the Sampler for level, so outputf/output
+// can drop messages at that level instead of spamming disk on a
+// looping error. Pass nil to disable sampling for level.
+func SetSampler(level int, s Sampler) {
+	samplerMu.Lock()
+	defer samplerMu.Unlock()
+	samplers[level] = s
+}
+
+// Stats returns, for each level, the number of messages emitted and
+// the number dropped by a Sampler, so operators can see suppression.
+func Stats() map[int]LevelStats {
+	result := make(map[int]LevelStats, numLevels)
+	for l := LevelTrace; l <= LevelError; l++ {
+		result[l] = LevelStats{
+			Emitted: atomic.LoadUint64(&stats[l].Emitted),
+			Dropped: atomic.LoadUint64(&stats[l].Dropped),
+		}
+	}
+	return result
+}
+
+// sampleAllow reports whether a message at level, identified by key,
+// should be emitted, bumping the matching Stats counter either way.
+func sampleAllow(level int, key uintptr) bool {
+	samplerMu.RLock()
+	s := samplers[level]
+	samplerMu.RUnlock()
+	if s == nil || s.Allow(key) {
+		atomic.AddUint64(&stats[level].Emitted, 1)
+		return true
+	}
+	atomic.AddUint64(&stats[level].Dropped, 1)
+	return false
+}
+
+// formatKey derives a stable per-call-site key from a format string's
+// backing storage, so the sampler returned by NewBurstSampler can count
+// log sites independently of one another. Call sites whose format
+// strings are identical literals can share backing storage (the
+// toolchain stores each distinct string constant once) and then share
+// a key.
+func formatKey(format string) uintptr {
+	return uintptr(unsafe.Pointer(unsafe.StringData(format)))
+}
+
+// rateSampler is a token-bucket limiter: tokens refill continuously at
+// n per per, and a message is allowed only while a token is
+// available.
+type rateSampler struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	refill   float64 // tokens per second
+	last     time.Time
+}
+
+// NewRateSampler returns a Sampler that allows at most n messages per
+// per, smoothed as a continuously refilling token bucket rather than
+// a hard window.
+func NewRateSampler(n int, per time.Duration) Sampler {
+	return &rateSampler{
+		capacity: float64(n),
+		tokens:   float64(n),
+		refill:   float64(n) / per.Seconds(),
+		last:     time.Now(),
+	}
+}
+
+func (r *rateSampler) Allow(uintptr) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	r.tokens += now.Sub(r.last).Seconds() * r.refill
+	if r.tokens > r.capacity {
+		r.tokens = r.capacity
+	}
+	r.last = now
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
+
+// burstSampler allows the first `first` messages for a given key,
+// then only every `thereafter`-th one, so a tight error loop still
+// surfaces occasionally instead of going silent.
+type burstSampler struct {
+	first      int
+	thereafter int
+
+	mu     sync.Mutex
+	counts map[uintptr]int
+}
+
+// NewBurstSampler returns a Sampler that allows the first `first`
+// messages for a given key, then every `thereafter`-th message after
+// that, keyed independently per call site.
+func NewBurstSampler(first, thereafter int) Sampler {
+	return &burstSampler{first: first, thereafter: thereafter, counts: make(map[uintptr]int)}
+}
+
+func (b *burstSampler) Allow(key uintptr) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := b.counts[key]
+	b.counts[key] = n + 1
+	if n < b.first {
+		return true
+	}
+	if b.thereafter <= 0 {
+		return false
+	}
+	return (n-b.first)%b.thereafter == 0
+}
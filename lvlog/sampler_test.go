@@ -0,0 +1,54 @@
+package lvlog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateSamplerAllowsNPerPeriodThenDrops(t *testing.T) {
+	// A long period keeps the bucket's refill negligible across this
+	// test's runtime, so it behaves as a plain burst-of-3 limiter.
+	s := NewRateSampler(3, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		if !s.Allow(0) {
+			t.Fatalf("call %d: expected allow within burst of 3, got drop", i)
+		}
+	}
+	if s.Allow(0) {
+		t.Fatalf("expected 4th call to be dropped once the bucket is spent")
+	}
+}
+
+func TestBurstSamplerAllowsFirstThenEveryNth(t *testing.T) {
+	s := NewBurstSampler(2, 3)
+	key := formatKey("some call site")
+
+	var got []bool
+	for i := 0; i < 8; i++ {
+		got = append(got, s.Allow(key))
+	}
+
+	want := []bool{true, true, true, false, false, true, false, false}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("call %d: got allow=%v, want %v (full sequence got=%v want=%v)", i, got[i], want[i], got, want)
+		}
+	}
+}
+
+func TestBurstSamplerTracksKeysIndependently(t *testing.T) {
+	s := NewBurstSampler(1, 0)
+	keyA := formatKey("site A")
+	keyB := formatKey("site B")
+
+	if !s.Allow(keyA) {
+		t.Fatalf("first call for keyA should be allowed")
+	}
+	if !s.Allow(keyB) {
+		t.Fatalf("first call for a distinct keyB should be allowed independently of keyA")
+	}
+	if s.Allow(keyA) {
+		t.Fatalf("second call for keyA should be dropped (thereafter=0)")
+	}
+}
@@ -0,0 +1,138 @@
+package lvlog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"regexp"
+	"sync"
+)
+
+// scrubPlaceholder replaces anything a scrubber matches when safe
+// logging is in effect.
+const scrubPlaceholder = "[scrubbed]"
+
+// unsafeLogging disables scrubbing when true. The package defaults to
+// safe (scrubbed) logging so it can be dropped into network daemons
+// that must not leak endpoint addresses to disk by default.
+var unsafeLogging bool
+
+var (
+	scrubMu   sync.RWMutex
+	scrubbers = map[string]*regexp.Regexp{
+		"ipv4":  regexp.MustCompile(`\b\d{1,3}(?:\.\d{1,3}){3}(?::\d+)?\b`),
+		"ipv6":  regexp.MustCompile(`\b(?:[0-9a-fA-F]{1,4}:){2,7}[0-9a-fA-F]{1,4}\b`),
+		"email": regexp.MustCompile(`[\w.+-]+@[\w-]+(?:\.[\w-]+)+`),
+	}
+)
+
+// InitSafeMode toggles whether logged output is scrubbed of addresses
+// and other potentially sensitive tokens before being written to the
+// configured writers. Pass unsafe=true to log raw, unscrubbed values;
+// the package defaults to safe (scrubbed) logging.
+func InitSafeMode(unsafe bool) {
+	unsafeLogging = unsafe
+}
+
+// RegisterScrubber adds a named regex-based scrubber applied by Scrub
+// and by the package-level logging functions. Registering under an
+// existing name replaces its pattern.
+func RegisterScrubber(name string, re *regexp.Regexp) {
+	scrubMu.Lock()
+	defer scrubMu.Unlock()
+	scrubbers[name] = re
+}
+
+// Scrub renders v as a string and, unless safe logging has been
+// disabled via InitSafeMode, elides IP addresses, net.Addr values,
+// email-like tokens, and any RegisterScrubber patterns with
+// scrubPlaceholder. The IPv6 pattern matches runs of at least three
+// colon-separated hex groups, so addresses compressed with "::" are
+// missed or only partly elided (::1 and fe80::1 are left intact).
+func Scrub(v interface{}) string {
+	if a, ok := v.(net.Addr); ok {
+		if unsafeLogging {
+			return a.String()
+		}
+		return scrubPlaceholder
+	}
+	return scrubString(fmt.Sprint(v))
+}
+
+// scrubString applies every registered scrubber to s, or returns s
+// unchanged when safe logging is disabled.
+func scrubString(s string) string {
+	if unsafeLogging {
+		return s
+	}
+	scrubMu.RLock()
+	defer scrubMu.RUnlock()
+	for _, re := range scrubbers {
+		s = re.ReplaceAllString(s, scrubPlaceholder)
+	}
+	return s
+}
+
+// scrubbingHandler wraps a slog.Handler so every record's message and
+// attribute values are scrubbed, as scrubAttr describes, before
+// reaching it. This is the one
+// choke point both the legacy Debugf/Infof/... path and the
+// DebugCtx/InfoCtx/.../With context-aware API funnel through (they
+// share std's per-level loggers), so neither can bypass safe mode.
+type scrubbingHandler struct {
+	slog.Handler
+}
+
+func newScrubbingHandler(h slog.Handler) slog.Handler {
+	return &scrubbingHandler{Handler: h}
+}
+
+func (h *scrubbingHandler) Handle(ctx context.Context, r slog.Record) error {
+	nr := slog.NewRecord(r.Time, r.Level, scrubString(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(scrubAttr(a))
+		return true
+	})
+	return h.Handler.Handle(ctx, nr)
+}
+
+func (h *scrubbingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	scrubbed := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		scrubbed[i] = scrubAttr(a)
+	}
+	return &scrubbingHandler{Handler: h.Handler.WithAttrs(scrubbed)}
+}
+
+func (h *scrubbingHandler) WithGroup(name string) slog.Handler {
+	return &scrubbingHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// scrubAttr scrubs a single attribute's value: strings are run
+// through scrubString, net.Addr values are elided the same way Scrub
+// elides them, groups are scrubbed attribute by attribute, and
+// everything else (including other values such as net.IP or error)
+// passes through untouched.
+func scrubAttr(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, scrubString(a.Value.String()))
+	case slog.KindGroup:
+		group := a.Value.Group()
+		scrubbed := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			scrubbed[i] = scrubAttr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(scrubbed...)}
+	case slog.KindAny:
+		if _, ok := a.Value.Any().(net.Addr); ok {
+			if unsafeLogging {
+				return a
+			}
+			return slog.String(a.Key, scrubPlaceholder)
+		}
+	}
+	return a
+}
@@ -0,0 +1,74 @@
+package lvlog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+// withStdConfig points std at the given level/writers and restores the
+// prior configuration afterwards, so tests don't leak global state.
+func withStdConfig(t *testing.T, lvl int, writers map[int]io.Writer) {
+	t.Helper()
+	origLevel, origWriters, origPkgLevel := std.level, std.writers, level
+	if err := InitLevelAndWriters(lvl, writers); err != nil {
+		t.Fatalf("InitLevelAndWriters: %v", err)
+	}
+	t.Cleanup(func() {
+		level = origPkgLevel
+		std.init(origLevel, origWriters)
+	})
+}
+
+func TestInitLevelAndWritersRoutesCtxAPI(t *testing.T) {
+	var infoBuf, errBuf bytes.Buffer
+	withStdConfig(t, LevelError, map[int]io.Writer{
+		LevelInfo:  &infoBuf,
+		LevelError: &errBuf,
+	})
+
+	ctx := context.Background()
+	InfoCtx(ctx, "should be suppressed below the configured level")
+	ErrorCtx(ctx, "should reach errBuf")
+
+	if infoBuf.Len() != 0 {
+		t.Fatalf("InfoCtx wrote %q, want nothing (level is LevelError)", infoBuf.String())
+	}
+	if !strings.Contains(errBuf.String(), "should reach errBuf") {
+		t.Fatalf("ErrorCtx did not write to its configured writer, got %q", errBuf.String())
+	}
+}
+
+func TestCtxAPIScrubsAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	withStdConfig(t, LevelInfo, map[int]io.Writer{LevelInfo: &buf})
+
+	addr := &net.TCPAddr{IP: net.ParseIP("203.0.113.5"), Port: 1234}
+	InfoCtx(context.Background(), "connection", "remote", addr)
+
+	out := buf.String()
+	if strings.Contains(out, "203.0.113.5") {
+		t.Fatalf("InfoCtx leaked raw address into log output: %q", out)
+	}
+	if !strings.Contains(out, scrubPlaceholder) {
+		t.Fatalf("expected scrubbed address placeholder in output, got %q", out)
+	}
+}
+
+func TestCtxAPIUnsafeModeSkipsScrubbing(t *testing.T) {
+	var buf bytes.Buffer
+	withStdConfig(t, LevelInfo, map[int]io.Writer{LevelInfo: &buf})
+
+	InitSafeMode(true)
+	t.Cleanup(func() { InitSafeMode(false) })
+
+	addr := &net.TCPAddr{IP: net.ParseIP("203.0.113.5"), Port: 1234}
+	InfoCtx(context.Background(), "connection", "remote", addr)
+
+	if !strings.Contains(buf.String(), "203.0.113.5") {
+		t.Fatalf("expected raw address with unsafe logging enabled, got %q", buf.String())
+	}
+}
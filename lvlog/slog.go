@@ -0,0 +1,126 @@
+package lvlog
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log/slog"
+)
+
+// Recognized values for the --log-format flag.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// logFormat stores the configured structured-logging output format.
+var logFormat = FormatText
+
+func init() {
+	flag.StringVar(&logFormat, "log-format", FormatText, "log output format, can only be text or json")
+}
+
+// toSlogLevel converts one of the Level* constants to the equivalent
+// slog.Level.
+func toSlogLevel(l int) slog.Level {
+	return slog.Level((l - LevelInfo) * 4)
+}
+
+// newHandler builds a slog.Handler for w using the configured
+// logFormat, filtering out records below lvl, and scrubbing every
+// record per the current safe-logging mode (see scrubbingHandler).
+func newHandler(w io.Writer, lvl int) slog.Handler {
+	opts := &slog.HandlerOptions{Level: toSlogLevel(lvl)}
+	var h slog.Handler
+	if logFormat == FormatJSON {
+		h = slog.NewJSONHandler(w, opts)
+	} else {
+		h = slog.NewTextHandler(w, opts)
+	}
+	return newScrubbingHandler(h)
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying logger, retrievable via
+// FromContext.
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger stored in ctx by NewContext, or
+// std's Info-level logger if ctx carries none. Since std is the same
+// Logger InitLevelAndWriters/InitLevelAndFile configure, the fallback
+// stays current with the package's level and writers rather than
+// pinned to a separately-tracked default.
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return std.loggerFor(LevelInfo)
+}
+
+// attrsCarrier wraps the real slog.Handler With binds its attrs to,
+// remembering those attrs so loggerForCtx can re-apply them to a
+// different level's handler. Without this, a logger built by With and
+// threaded through NewContext would stay pinned to whatever level
+// With happened to bind (LevelInfo) no matter which *Ctx function
+// later logs through it, silently misrouting e.g. ErrorCtx to the
+// INFO destination.
+type attrsCarrier struct {
+	slog.Handler
+	attrs []any
+}
+
+// With returns a logger with attrs attached, so callers can thread
+// persistent fields (request_id, user_id, ...) into the records
+// emitted through it. Used directly (With(...).Info(...)), it logs
+// through std's Info-level destination; threaded via NewContext and a
+// *Ctx function (NewContext(ctx, With(...)); ErrorCtx(ctx, ...)), its
+// attrs are re-applied to that call's actual level instead.
+func With(attrs ...any) *slog.Logger {
+	bound := std.loggerFor(LevelInfo).With(attrs...)
+	return slog.New(&attrsCarrier{Handler: bound.Handler(), attrs: attrs})
+}
+
+// loggerForCtx returns the logger to use for a *Ctx call at level.
+// A logger built by With (identified by its attrsCarrier handler) has
+// its attrs re-applied to level's own logger, so per-level routing
+// still applies. Any other ctx-stored logger is used as-is: a caller
+// that attaches a fully custom *slog.Logger via NewContext is opting
+// that logger out of per-level routing. With no stored logger at all,
+// it falls back to std's logger for level.
+func loggerForCtx(ctx context.Context, level int) *slog.Logger {
+	l, ok := ctx.Value(contextKey{}).(*slog.Logger)
+	if !ok {
+		return std.loggerFor(level)
+	}
+	if carrier, ok := l.Handler().(*attrsCarrier); ok {
+		return std.loggerFor(level).With(carrier.attrs...)
+	}
+	return l
+}
+
+// DebugCtx logs msg at the "debug" level using the logger found in
+// ctx (see loggerForCtx), attaching attrs as structured fields.
+func DebugCtx(ctx context.Context, msg string, attrs ...any) {
+	loggerForCtx(ctx, LevelDebug).Log(ctx, slog.LevelDebug, msg, attrs...)
+}
+
+// InfoCtx logs msg at the "info" level using the logger found in ctx
+// (see loggerForCtx), attaching attrs as structured fields.
+func InfoCtx(ctx context.Context, msg string, attrs ...any) {
+	loggerForCtx(ctx, LevelInfo).Log(ctx, slog.LevelInfo, msg, attrs...)
+}
+
+// WarningCtx logs msg at the "warning" level using the logger found in
+// ctx (see loggerForCtx), attaching attrs as structured fields.
+func WarningCtx(ctx context.Context, msg string, attrs ...any) {
+	loggerForCtx(ctx, LevelWarning).Log(ctx, slog.LevelWarn, msg, attrs...)
+}
+
+// ErrorCtx logs msg at the "error" level using the logger found in
+// ctx (see loggerForCtx), attaching attrs as structured fields.
+func ErrorCtx(ctx context.Context, msg string, attrs ...any) {
+	loggerForCtx(ctx, LevelError).Log(ctx, slog.LevelError, msg, attrs...)
+}
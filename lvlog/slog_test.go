@@ -0,0 +1,64 @@
+package lvlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+// withLogFormat points logFormat at format and restores the prior
+// value afterwards, so tests don't leak global state.
+func withLogFormat(t *testing.T, format string) {
+	t.Helper()
+	orig := logFormat
+	logFormat = format
+	t.Cleanup(func() {
+		logFormat = orig
+		std.rebuild()
+	})
+}
+
+func TestFormatJSONProducesParseableRecords(t *testing.T) {
+	withLogFormat(t, FormatJSON)
+
+	var buf bytes.Buffer
+	withStdConfig(t, LevelInfo, map[int]io.Writer{LevelInfo: &buf})
+
+	Infof("user %s logged in", "alice")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("output is not valid JSON: %v\noutput: %s", err, buf.String())
+	}
+	if msg, _ := record["msg"].(string); msg != "user alice logged in" {
+		t.Fatalf("got msg %q, want %q", msg, "user alice logged in")
+	}
+	if level, _ := record["level"].(string); level != "INFO" {
+		t.Fatalf("got level %q, want %q", level, "INFO")
+	}
+}
+
+func TestWithThroughNewContextRoutesToCalledLevel(t *testing.T) {
+	var infoBuf, errBuf bytes.Buffer
+	withStdConfig(t, LevelInfo, map[int]io.Writer{
+		LevelInfo:  &infoBuf,
+		LevelError: &errBuf,
+	})
+
+	ctx := NewContext(context.Background(), With("request_id", "abc123"))
+	ErrorCtx(ctx, "db connection failed")
+
+	if infoBuf.Len() != 0 {
+		t.Fatalf("ErrorCtx leaked into the Info writer: %q", infoBuf.String())
+	}
+	out := errBuf.String()
+	if !strings.Contains(out, "db connection failed") {
+		t.Fatalf("ErrorCtx did not write to the Error writer, got %q", out)
+	}
+	if !strings.Contains(out, "request_id=abc123") {
+		t.Fatalf("With's attrs were dropped when routed to Error, got %q", out)
+	}
+}